@@ -0,0 +1,16 @@
+package sqlingo
+
+// BytesField represents a []byte field, for BLOB/binary columns where
+// rendering as string would mangle non-UTF8 content.
+type BytesField interface {
+	Field
+}
+
+type bytesField struct {
+	field
+}
+
+// NewBytesField creates a BytesField for the given table and column name.
+func NewBytesField(table Table, name string) BytesField {
+	return bytesField{field: newField(table, name)}
+}
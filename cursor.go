@@ -0,0 +1,427 @@
+package sqlingo
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"unsafe"
+
+	"github.com/shopspring/decimal"
+)
+
+// Cursor iterates over the rows returned by a query.
+type Cursor interface {
+	// Next advances the cursor to the next row. It returns false when there
+	// are no more rows.
+	Next() bool
+
+	// Scan copies the columns of the current row into dest. A dest that
+	// points (through any number of pointer indirections) to a struct
+	// consumes one column per exported field of that struct, in order;
+	// every other dest consumes exactly one column. A NULL column leaves a
+	// nil pointer rather than a zero value.
+	Scan(dest ...interface{}) error
+
+	// ScanStruct copies the columns of the current row into the exported
+	// fields of the struct pointed to by dest (through any number of pointer
+	// indirections), in field declaration order, honoring the same
+	// deep-pointer/nullable rules as Scan. It's a cached-reflection
+	// equivalent of Scan(&dest) for the common case of scanning a whole row
+	// into a generated *Model struct.
+	ScanStruct(dest interface{}) error
+
+	// Close closes the cursor, releasing its underlying resources.
+	Close() error
+}
+
+type cursorImpl struct {
+	rows *sql.Rows
+}
+
+func newCursor(rows *sql.Rows) Cursor {
+	return &cursorImpl{rows: rows}
+}
+
+func (c *cursorImpl) Next() bool {
+	return c.rows.Next()
+}
+
+func (c *cursorImpl) Close() error {
+	return c.rows.Close()
+}
+
+func (c *cursorImpl) Scan(dest ...interface{}) error {
+	if len(dest) == 0 {
+		return nil
+	}
+
+	columnCount := 0
+	for _, d := range dest {
+		columnCount += columnsConsumedBy(reflect.ValueOf(d))
+	}
+
+	rawValues := make([]interface{}, columnCount)
+	for i := range rawValues {
+		var v interface{}
+		rawValues[i] = &v
+	}
+	if err := c.rows.Scan(rawValues...); err != nil {
+		return err
+	}
+
+	cursor := 0
+	for _, d := range dest {
+		consumed, err := scanInto(reflect.ValueOf(d), rawValues[cursor:])
+		if err != nil {
+			return err
+		}
+		cursor += consumed
+	}
+	return nil
+}
+
+// structScanField is one field of a cached structScanPlan: its byte offset
+// within the struct and its declared type, enough to address it directly
+// with unsafe.Pointer without re-walking reflect.Type.Field on every row.
+type structScanField struct {
+	offset uintptr
+	typ    reflect.Type
+}
+
+type structScanPlan struct {
+	fields []structScanField
+}
+
+var structScanPlans sync.Map // map[reflect.Type]*structScanPlan
+
+func getStructScanPlan(t reflect.Type) *structScanPlan {
+	if cached, ok := structScanPlans.Load(t); ok {
+		return cached.(*structScanPlan)
+	}
+	plan := &structScanPlan{}
+	appendStructScanFields(&plan.fields, t, 0)
+	actual, _ := structScanPlans.LoadOrStore(t, plan)
+	return actual.(*structScanPlan)
+}
+
+// appendStructScanFields walks t's exported fields, adding baseOffset to
+// each field's offset, and recurses into nested (non-decimal.Decimal)
+// structs so that ScanStruct consumes one column per leaf field. (Scan, via
+// columnsConsumedBy, counts one column per top-level exported field instead.)
+func appendStructScanFields(fields *[]structScanField, t reflect.Type, baseOffset uintptr) {
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		if structField.PkgPath != "" {
+			continue // unexported
+		}
+		// Only a plain (non-pointer) nested struct can be recursed into with
+		// a precomputed offset; a *struct field's target lives at a separate
+		// heap allocation and is scanned as a single leaf value.
+		if structField.Type.Kind() == reflect.Struct && structField.Type != reflect.TypeOf(decimal.Decimal{}) {
+			appendStructScanFields(fields, structField.Type, baseOffset+structField.Offset)
+			continue
+		}
+		*fields = append(*fields, structScanField{offset: baseOffset + structField.Offset, typ: structField.Type})
+	}
+}
+
+func (c *cursorImpl) ScanStruct(dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr {
+		return fmt.Errorf("sqlingo: ScanStruct destination must be a pointer, got %s", v.Type())
+	}
+
+	t := terminalType(v.Type().Elem())
+	if t.Kind() != reflect.Struct || t == reflect.TypeOf(decimal.Decimal{}) {
+		return fmt.Errorf("sqlingo: ScanStruct destination must point to a struct, got %s", t)
+	}
+	plan := getStructScanPlan(t)
+
+	rawValues := make([]interface{}, len(plan.fields))
+	for i := range rawValues {
+		var v interface{}
+		rawValues[i] = &v
+	}
+	if err := c.rows.Scan(rawValues...); err != nil {
+		return err
+	}
+
+	base := unsafe.Pointer(allocatePointerChain(v.Elem()).UnsafeAddr())
+	for i, field := range plan.fields {
+		fieldValue := reflect.NewAt(field.typ, unsafe.Pointer(uintptr(base)+field.offset)).Elem()
+		if err := assignScalar(fieldValue, *(rawValues[i].(*interface{}))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func columnsConsumedBy(v reflect.Value) int {
+	t := terminalType(v.Type())
+	if t.Kind() == reflect.Struct && t != reflect.TypeOf(decimal.Decimal{}) {
+		exported := 0
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath == "" {
+				exported++
+			}
+		}
+		return exported
+	}
+	return 1
+}
+
+// scanInto populates the variable pointed to by v (v.Elem(), through any
+// depth of further pointers if it is itself a pointer) from raw, and returns
+// how many elements of raw it consumed.
+func scanInto(v reflect.Value, raw []interface{}) (consumed int, err error) {
+	if v.Kind() != reflect.Ptr {
+		return 0, fmt.Errorf("sqlingo: Scan destination must be a pointer, got %s", v.Type())
+	}
+	variable := v.Elem()
+
+	t := terminalType(variable.Type())
+	if t.Kind() == reflect.Struct && t != reflect.TypeOf(decimal.Decimal{}) {
+		target := allocatePointerChain(variable)
+		column := 0
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath != "" {
+				continue // unexported
+			}
+			if _, err := scanInto(target.Field(i).Addr(), raw[column:column+1]); err != nil {
+				return 0, err
+			}
+			column++
+		}
+		return column, nil
+	}
+
+	if err := assignScalar(variable, *(raw[0].(*interface{}))); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// terminalType unwraps any number of pointer levels and returns the base type.
+func terminalType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// allocatePointerChain walks a chain of pointers (e.g. ****int), allocating
+// each intermediate level as needed, and returns the settable base value.
+func allocatePointerChain(variable reflect.Value) reflect.Value {
+	for variable.Kind() == reflect.Ptr {
+		if variable.IsNil() {
+			variable.Set(reflect.New(variable.Type().Elem()))
+		}
+		variable = variable.Elem()
+	}
+	return variable
+}
+
+// assignScalar assigns raw (as returned by database/sql, or nil for NULL) to
+// variable, allocating through any depth of pointers. A nil raw value sets
+// variable itself to nil rather than allocating a zero value.
+func assignScalar(variable reflect.Value, raw interface{}) error {
+	if raw == nil {
+		variable.Set(reflect.Zero(variable.Type()))
+		return nil
+	}
+
+	target := allocatePointerChain(variable)
+	converted, err := convertScalar(raw, target.Type())
+	if err != nil {
+		return err
+	}
+	target.Set(converted)
+	return nil
+}
+
+func convertScalar(raw interface{}, targetType reflect.Type) (reflect.Value, error) {
+	if targetType == reflect.TypeOf(decimal.Decimal{}) {
+		return convertDecimal(raw)
+	}
+	if targetType == reflect.TypeOf([]byte(nil)) {
+		return convertBytes(raw)
+	}
+
+	switch targetType.Kind() {
+	case reflect.Bool:
+		return convertBool(raw)
+	case reflect.String:
+		return convertStringKind(raw, targetType)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return convertIntKind(raw, targetType)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return convertUintKind(raw, targetType)
+	case reflect.Float32, reflect.Float64:
+		return convertFloatKind(raw, targetType)
+	}
+
+	rawValue := reflect.ValueOf(raw)
+	if rawValue.Type().ConvertibleTo(targetType) {
+		return rawValue.Convert(targetType), nil
+	}
+	return reflect.Value{}, fmt.Errorf("sqlingo: cannot scan %T into %s", raw, targetType)
+}
+
+// rawAsString renders a driver.Value-shaped raw value (as database/sql hands
+// back: []byte, string, one of the int64/float64/bool scan types, or a
+// caller-provided int/float32) as a string, for driver/value combinations
+// (e.g. a numeric column into a Go string field) where Go's own conversion
+// rules don't apply but the textual representation is well-defined.
+func rawAsString(raw interface{}) (string, bool) {
+	switch v := raw.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case int:
+		return strconv.Itoa(v), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
+
+func convertStringKind(raw interface{}, targetType reflect.Type) (reflect.Value, error) {
+	s, ok := rawAsString(raw)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("sqlingo: cannot scan %T into %s", raw, targetType)
+	}
+	return reflect.ValueOf(s).Convert(targetType), nil
+}
+
+func convertIntKind(raw interface{}, targetType reflect.Type) (reflect.Value, error) {
+	switch v := raw.(type) {
+	case int64:
+		return reflect.ValueOf(v).Convert(targetType), nil
+	case int:
+		return reflect.ValueOf(int64(v)).Convert(targetType), nil
+	case float64:
+		return reflect.ValueOf(int64(v)).Convert(targetType), nil
+	case float32:
+		return reflect.ValueOf(int64(v)).Convert(targetType), nil
+	case string, []byte:
+		s, _ := rawAsString(v)
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("sqlingo: cannot scan %q into %s: %w", s, targetType, err)
+		}
+		return reflect.ValueOf(n).Convert(targetType), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("sqlingo: cannot scan %T into %s", raw, targetType)
+	}
+}
+
+func convertUintKind(raw interface{}, targetType reflect.Type) (reflect.Value, error) {
+	switch v := raw.(type) {
+	case uint64:
+		return reflect.ValueOf(v).Convert(targetType), nil
+	case int64:
+		return reflect.ValueOf(uint64(v)).Convert(targetType), nil
+	case int:
+		return reflect.ValueOf(uint64(v)).Convert(targetType), nil
+	case float64:
+		return reflect.ValueOf(uint64(v)).Convert(targetType), nil
+	case float32:
+		return reflect.ValueOf(uint64(v)).Convert(targetType), nil
+	case string, []byte:
+		s, _ := rawAsString(v)
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("sqlingo: cannot scan %q into %s: %w", s, targetType, err)
+		}
+		return reflect.ValueOf(n).Convert(targetType), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("sqlingo: cannot scan %T into %s", raw, targetType)
+	}
+}
+
+func convertFloatKind(raw interface{}, targetType reflect.Type) (reflect.Value, error) {
+	switch v := raw.(type) {
+	case float64:
+		return reflect.ValueOf(v).Convert(targetType), nil
+	case float32:
+		return reflect.ValueOf(float64(v)).Convert(targetType), nil
+	case int64:
+		return reflect.ValueOf(float64(v)).Convert(targetType), nil
+	case int:
+		return reflect.ValueOf(float64(v)).Convert(targetType), nil
+	case string, []byte:
+		s, _ := rawAsString(v)
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("sqlingo: cannot scan %q into %s: %w", s, targetType, err)
+		}
+		return reflect.ValueOf(f).Convert(targetType), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("sqlingo: cannot scan %T into %s", raw, targetType)
+	}
+}
+
+func convertDecimal(raw interface{}) (reflect.Value, error) {
+	switch v := raw.(type) {
+	case decimal.Decimal:
+		return reflect.ValueOf(v), nil
+	case []byte:
+		d, err := decimal.NewFromString(string(v))
+		return reflect.ValueOf(d), err
+	case string:
+		d, err := decimal.NewFromString(v)
+		return reflect.ValueOf(d), err
+	case float32:
+		return reflect.ValueOf(decimal.NewFromFloat(float64(v))), nil
+	case float64:
+		return reflect.ValueOf(decimal.NewFromFloat(v)), nil
+	case int64:
+		return reflect.ValueOf(decimal.NewFromInt(v)), nil
+	case int:
+		return reflect.ValueOf(decimal.NewFromInt(int64(v))), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("sqlingo: cannot scan %T into decimal.Decimal", raw)
+	}
+}
+
+func convertBool(raw interface{}) (reflect.Value, error) {
+	switch v := raw.(type) {
+	case bool:
+		return reflect.ValueOf(v), nil
+	case []byte:
+		return reflect.ValueOf(len(v) > 0 && v[0] != 0), nil
+	case string:
+		return reflect.ValueOf(v != "" && v[0] != 0), nil
+	case int64:
+		return reflect.ValueOf(v != 0), nil
+	case int:
+		return reflect.ValueOf(v != 0), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("sqlingo: cannot scan %T into bool", raw)
+	}
+}
+
+func convertBytes(raw interface{}) (reflect.Value, error) {
+	switch v := raw.(type) {
+	case []byte:
+		b := make([]byte, len(v))
+		copy(b, v)
+		return reflect.ValueOf(b), nil
+	case string:
+		return reflect.ValueOf([]byte(v)), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("sqlingo: cannot scan %T into []byte", raw)
+	}
+}
@@ -2,9 +2,12 @@ package sqlingo
 
 import (
 	"database/sql/driver"
+	"fmt"
 	"io"
 	"strconv"
 	"testing"
+
+	"github.com/shopspring/decimal"
 )
 
 type mockDriver struct{}
@@ -27,7 +30,7 @@ type mockRows struct {
 }
 
 func (m mockRows) Columns() []string {
-	return []string{"a", "b", "c", "d", "e", "f", "g"}[:m.columnCount]
+	return []string{"a", "b", "c", "d", "e", "f", "g", "h", "i"}[:m.columnCount]
 }
 
 func (m mockRows) Close() error {
@@ -55,6 +58,10 @@ func (m *mockRows) Next(dest []driver.Value) error {
 			dest[i] = dest[0]
 		case 6:
 			dest[i] = nil
+		case 7:
+			dest[i] = []byte{0, 1, byte(m.cursorPosition)}
+		case 8:
+			dest[i] = fmt.Sprintf("%d.50", m.cursorPosition)
 		}
 	}
 	return nil
@@ -87,25 +94,31 @@ func TestCursor(t *testing.T) {
 		C    float32
 		D, E bool
 	}
-	var f ****int // deep pointer
-	var g *int    // always null
+	var f ****int             // deep pointer
+	var g *int                // always null
+	var h **[]byte            // deep pointer to []byte
+	var dec **decimal.Decimal // deep pointer to decimal.Decimal
 
 	for i := 1; i <= 10; i++ {
 		if !cursor.Next() {
 			t.Errorf("a")
 		}
 		g = &i
-		if err := cursor.Scan(&a, &b, &cde, &f, &g); err != nil {
+		if err := cursor.Scan(&a, &b, &cde, &f, &g, &h, &dec); err != nil {
 			t.Errorf("%v", err)
 		}
+		wantBytes := []byte{0, 1, byte(i)}
+		wantDecimal := decimal.NewFromFloat(float64(i) + 0.5)
 		if a != i ||
 			b != strconv.Itoa(i) ||
 			cde.C != float32(i) ||
 			cde.D != (i%2 == 1) ||
 			cde.E != cde.E ||
 			****f != i ||
-			g != nil {
-			t.Error(a, b, cde.C, cde.D, cde.E, ****f, g)
+			g != nil ||
+			string(**h) != string(wantBytes) ||
+			!(**dec).Equal(wantDecimal) {
+			t.Error(a, b, cde.C, cde.D, cde.E, ****f, g, **h, **dec)
 		}
 		if err := cursor.Scan(); err != nil {
 			t.Errorf("%v", err)
@@ -114,7 +127,9 @@ func TestCursor(t *testing.T) {
 		var s string
 		var b ****bool
 		var p *string
-		if err := cursor.Scan(&s, &s, &s, &b, &s, &s, &p); err != nil {
+		var bs []byte
+		var d decimal.Decimal
+		if err := cursor.Scan(&s, &s, &s, &b, &s, &s, &p, &bs, &d); err != nil {
 			t.Error(err)
 		}
 	}
@@ -126,3 +141,48 @@ func TestCursor(t *testing.T) {
 	}
 
 }
+
+type scanStructDest struct {
+	A int
+	B string
+	C float32
+	D bool
+	E bool
+	F string
+	G *int
+	H []byte
+	I decimal.Decimal
+}
+
+func TestCursorScanStruct(t *testing.T) {
+	db := newMockDatabase()
+	cursor, _ := db.Query("dummy sql")
+
+	for i := 1; i <= 10; i++ {
+		if !cursor.Next() {
+			t.Errorf("a")
+		}
+
+		dest := new(scanStructDest) // deep pointer
+		if err := cursor.ScanStruct(&dest); err != nil {
+			t.Error(err)
+		}
+		wantBytes := []byte{0, 1, byte(i)}
+		wantDecimal := decimal.NewFromFloat(float64(i) + 0.5)
+		if dest.A != i ||
+			dest.B != strconv.Itoa(i) ||
+			dest.C != float32(i) ||
+			dest.D != (i%2 == 1) ||
+			dest.G != nil ||
+			string(dest.H) != string(wantBytes) ||
+			!dest.I.Equal(wantDecimal) {
+			t.Error(dest)
+		}
+	}
+	if cursor.Next() {
+		t.Errorf("d")
+	}
+	if err := cursor.Close(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,17 @@
+package sqlingo
+
+// DecimalField represents a decimal/numeric/money field backed by
+// github.com/shopspring/decimal.Decimal, for columns where converting to
+// float64 would silently lose precision.
+type DecimalField interface {
+	Field
+}
+
+type decimalField struct {
+	field
+}
+
+// NewDecimalField creates a DecimalField for the given table and column name.
+func NewDecimalField(table Table, name string) DecimalField {
+	return decimalField{field: newField(table, name)}
+}
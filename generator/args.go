@@ -14,7 +14,7 @@ type options struct {
 func printUsageAndExit(exampleDataSourceName string) {
 	cmd := os.Args[0]
 	_, _ = fmt.Fprintf(os.Stderr, `Usage:
-	%s -o outpath -d datasource [-t table1,table2,...] [-forcecases ID,IDs,HTML] dataSourceName
+	%s -o outpath -dbc datasource [-t table1,table2,...] [-forcecases ID,IDs,HTML] [-migrate] dataSourceName
 Example:
 	%s "%s"
 `, cmd, cmd, fmt.Sprintf("-o ./ -d %s", exampleDataSourceName))
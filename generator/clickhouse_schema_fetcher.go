@@ -0,0 +1,143 @@
+package generator
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+type clickHouseSchemaFetcher struct {
+	db *sql.DB
+}
+
+func newClickHouseSchemaFetcher(db *sql.DB) schemaFetcher {
+	return clickHouseSchemaFetcher{db: db}
+}
+
+func (f clickHouseSchemaFetcher) GetDatabaseName() (dbName string, err error) {
+	err = f.db.QueryRow("SELECT currentDatabase()").Scan(&dbName)
+	return
+}
+
+func (f clickHouseSchemaFetcher) GetTableNames() (tableNames []string, err error) {
+	rows, err := f.db.Query("SELECT name FROM system.tables WHERE database = currentDatabase() ORDER BY name")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName string
+		if err = rows.Scan(&tableName); err != nil {
+			return
+		}
+		tableNames = append(tableNames, tableName)
+	}
+	err = rows.Err()
+	return
+}
+
+func (f clickHouseSchemaFetcher) GetFieldDescriptors(tableName string) (fieldDescriptors []fieldDescriptor, err error) {
+	rows, err := f.db.Query(
+		"SELECT name, type, comment FROM system.columns WHERE database = currentDatabase() AND table = ? ORDER BY position",
+		tableName)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, rawType, comment string
+		if err = rows.Scan(&name, &rawType, &comment); err != nil {
+			return
+		}
+		fieldDescriptors = append(fieldDescriptors, parseClickHouseType(name, rawType, comment))
+	}
+	err = rows.Err()
+	return
+}
+
+func (f clickHouseSchemaFetcher) QuoteIdentifier(identifier string) string {
+	return "`" + identifier + "`"
+}
+
+// parseClickHouseType unwraps Nullable(...) and LowCardinality(...) wrappers
+// and extracts Decimal(P,S) precision/scale and the element type of Array(T),
+// so that getType can be driven off a plain type name via the "clickhouse"
+// type mapper registry.
+func parseClickHouseType(name, rawType, comment string) fieldDescriptor {
+	fieldDescriptor := fieldDescriptor{Name: name, Comment: comment}
+
+	t := rawType
+	if inner, ok := unwrapClickHouseType(t, "Nullable("); ok {
+		fieldDescriptor.AllowNull = true
+		t = inner
+	}
+	if inner, ok := unwrapClickHouseType(t, "LowCardinality("); ok {
+		t = inner
+	}
+
+	switch {
+	case strings.HasPrefix(t, "Decimal("):
+		fieldDescriptor.Type = "decimal"
+		params := strings.Split(t[len("Decimal("):len(t)-1], ",")
+		if len(params) == 2 {
+			fieldDescriptor.Size, _ = strconv.Atoi(strings.TrimSpace(params[0]))
+			fieldDescriptor.Scale, _ = strconv.Atoi(strings.TrimSpace(params[1]))
+		}
+	case strings.HasPrefix(t, "DateTime64"):
+		fieldDescriptor.Type = "datetime64"
+	case strings.HasPrefix(t, "FixedString("):
+		fieldDescriptor.Type = "fixedstring"
+	case strings.HasPrefix(t, "Array("):
+		fieldDescriptor.Type = "array"
+		fieldDescriptor.ElementType = strings.ToLower(t[len("Array(") : len(t)-1])
+	default:
+		fieldDescriptor.Type = strings.ToLower(t)
+	}
+	return fieldDescriptor
+}
+
+func unwrapClickHouseType(t, prefix string) (inner string, ok bool) {
+	if strings.HasPrefix(t, prefix) && strings.HasSuffix(t, ")") {
+		return t[len(prefix) : len(t)-1], true
+	}
+	return t, false
+}
+
+// clickHouseScalarGoTypes maps the ClickHouse scalar type names produced by
+// parseClickHouseType to their Go equivalents, for use both directly and as
+// the element type of Array(T) columns.
+var clickHouseScalarGoTypes = map[string]string{
+	"int8": "int8", "int16": "int16", "int32": "int32", "int64": "int64",
+	"uint8": "uint8", "uint16": "uint16", "uint32": "uint32", "uint64": "uint64",
+	"float32": "float32", "float64": "float64",
+	"string": "string", "fixedstring": "string",
+	"uuid": "string", "date": "string", "datetime": "string", "datetime64": "string",
+	"bool": "bool",
+}
+
+func init() {
+	for chType, goType := range clickHouseScalarGoTypes {
+		goType, chType := goType, chType
+		fieldClass := "StringField"
+		if goType == "bool" {
+			fieldClass = "BooleanField"
+		} else if strings.HasPrefix(goType, "int") || strings.HasPrefix(goType, "uint") || strings.HasPrefix(goType, "float") {
+			fieldClass = "NumberField"
+		}
+		RegisterTypeMapper("clickhouse", chType, func(fieldDescriptor fieldDescriptor) (string, string, string, error) {
+			return goType, fieldClass, "", nil
+		})
+	}
+	for _, sqlType := range []string{"decimal", "numeric", "money"} {
+		RegisterTypeMapper("clickhouse", sqlType, mapDecimalType)
+	}
+	RegisterTypeMapper("clickhouse", "array", func(fieldDescriptor fieldDescriptor) (goType, fieldClass, importPath string, err error) {
+		elemType, ok := clickHouseScalarGoTypes[fieldDescriptor.ElementType]
+		if !ok {
+			elemType = "interface{}"
+		}
+		return "[]" + elemType, "StringField", "", nil
+	})
+}
@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 const (
@@ -31,6 +33,12 @@ type fieldDescriptor struct {
 	Unsigned  bool
 	AllowNull bool
 	Comment   string
+	// Scale is the number of digits after the decimal point, as reported by
+	// the schemaFetcher for decimal/numeric columns (e.g. 4 for decimal(20,4)).
+	Scale int
+	// ElementType is the lowercased element type of an Array(T) column, as
+	// reported by the ClickHouse schemaFetcher; empty for non-array columns.
+	ElementType string
 }
 
 func convertToExportedIdentifier(s string, forceCases []string) string {
@@ -70,45 +78,150 @@ func convertToExportedIdentifier(s string, forceCases []string) string {
 	return result
 }
 
-func getType(fieldDescriptor fieldDescriptor) (goType string, fieldClass string, err error) {
-	switch strings.ToLower(fieldDescriptor.Type) {
-	case "tinyint":
-		goType = "int8"
-		fieldClass = "NumberField"
-	case "smallint":
-		goType = "int16"
-		fieldClass = "NumberField"
-	case "int", "mediumint":
-		goType = "int32"
-		fieldClass = "NumberField"
-	case "bigint", "integer":
-		goType = "int64"
-		fieldClass = "NumberField"
-	case "float", "double", "decimal", "real":
-		goType = "float64"
-		fieldClass = "NumberField"
-	case "char", "varchar", "text", "tinytext", "mediumtext", "longtext", "enum", "datetime", "date", "time", "timestamp", "json", "numeric", "character varying":
-		goType = "string"
-		fieldClass = "StringField"
-	case "binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob":
-		// TODO: use []byte ?
-		goType = "string"
-		fieldClass = "StringField"
-	case "geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon", "geometrycollection":
-		goType = "sqlingo.WellKnownBinary"
-		fieldClass = "WellKnownBinaryField"
-	case "bit":
-		if fieldDescriptor.Size == 1 {
-			goType = "bool"
-			fieldClass = "BooleanField"
+// splitColumnWords splits a column name into its constituent words on any
+// non-letter/non-digit separator (e.g. "_"), preserving the original case of
+// each word so renderTagName can recombine them in the requested tag case.
+func splitColumnWords(name string) []string {
+	var words []string
+	wordStart := true
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if wordStart {
+				words = append(words, "")
+				wordStart = false
+			}
+			words[len(words)-1] += string(r)
 		} else {
-			goType = "string"
-			fieldClass = "StringField"
+			wordStart = true
+		}
+	}
+	return words
+}
+
+// upperFirstRune returns word with its first rune upper-cased and the rest
+// unchanged. Unlike word[:1], it decodes a full rune, so multi-byte UTF-8
+// column names aren't corrupted.
+func upperFirstRune(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	if r == utf8.RuneError {
+		return word
+	}
+	return string(unicode.ToUpper(r)) + word[size:]
+}
+
+// lowerFirstRune is upperFirstRune's lower-casing counterpart.
+func lowerFirstRune(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	if r == utf8.RuneError {
+		return word
+	}
+	return string(unicode.ToLower(r)) + word[size:]
+}
+
+// renderTagName renders a column name as a json tag in the given case:
+// "snake" (the default, e.g. "user_id"), "camel" (e.g. "userId") or "pascal"
+// (e.g. "UserId").
+func renderTagName(columnName, tagCase string) string {
+	words := splitColumnWords(columnName)
+	switch tagCase {
+	case "pascal":
+		var b strings.Builder
+		for _, word := range words {
+			b.WriteString(upperFirstRune(word))
+		}
+		return b.String()
+	case "camel":
+		var b strings.Builder
+		for i, word := range words {
+			if i == 0 {
+				b.WriteString(lowerFirstRune(word))
+			} else {
+				b.WriteString(upperFirstRune(word))
+			}
 		}
+		return b.String()
 	default:
+		lowered := make([]string, len(words))
+		for i, word := range words {
+			lowered[i] = strings.ToLower(word)
+		}
+		return strings.Join(lowered, "_")
+	}
+}
+
+func init() {
+	register := func(sqlType, goType, fieldClass string) {
+		RegisterTypeMapper("*", sqlType, func(fieldDescriptor fieldDescriptor) (string, string, string, error) {
+			return goType, fieldClass, "", nil
+		})
+	}
+	register("tinyint", "int8", "NumberField")
+	register("smallint", "int16", "NumberField")
+	register("int", "int32", "NumberField")
+	register("mediumint", "int32", "NumberField")
+	register("bigint", "int64", "NumberField")
+	register("integer", "int64", "NumberField")
+	register("float", "float64", "NumberField")
+	register("double", "float64", "NumberField")
+	register("real", "float64", "NumberField")
+	for _, sqlType := range []string{"decimal", "numeric", "money"} {
+		RegisterTypeMapper("*", sqlType, mapDecimalType)
+	}
+	register("char", "string", "StringField")
+	register("varchar", "string", "StringField")
+	register("text", "string", "StringField")
+	register("tinytext", "string", "StringField")
+	register("mediumtext", "string", "StringField")
+	register("longtext", "string", "StringField")
+	register("enum", "string", "StringField")
+	register("datetime", "string", "StringField")
+	register("date", "string", "StringField")
+	register("time", "string", "StringField")
+	register("timestamp", "string", "StringField")
+	register("json", "string", "StringField")
+	register("character varying", "string", "StringField")
+	for _, sqlType := range []string{"binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob", "bytea"} {
+		RegisterTypeMapper("*", sqlType, mapBytesType)
+	}
+	for _, sqlType := range []string{"geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon", "geometrycollection"} {
+		RegisterTypeMapper("*", sqlType, func(fieldDescriptor fieldDescriptor) (string, string, string, error) {
+			return "sqlingo.WellKnownBinary", "WellKnownBinaryField", "", nil
+		})
+	}
+	RegisterTypeMapper("*", "bit", func(fieldDescriptor fieldDescriptor) (string, string, string, error) {
+		if fieldDescriptor.Size == 1 {
+			return "bool", "BooleanField", "", nil
+		}
+		return "string", "StringField", "", nil
+	})
+}
+
+// mapDecimalType renders decimal/numeric/money columns as
+// github.com/shopspring/decimal.Decimal so that precision isn't silently
+// lost, unless -decimal=float64 opts back into the legacy float64 behavior.
+func mapDecimalType(fieldDescriptor fieldDescriptor) (goType, fieldClass, importPath string, err error) {
+	if *decimalMode == "float64" {
+		return "float64", "NumberField", "", nil
+	}
+	return "decimal.Decimal", "DecimalField", "github.com/shopspring/decimal", nil
+}
+
+// mapBytesType renders BLOB/binary columns (and Postgres bytea) as []byte
+// instead of string, so scanning doesn't mangle non-UTF8 content.
+func mapBytesType(fieldDescriptor fieldDescriptor) (goType, fieldClass, importPath string, err error) {
+	return "[]byte", "BytesField", "", nil
+}
+
+func getType(driverName string, fieldDescriptor fieldDescriptor) (goType string, fieldClass string, importPath string, err error) {
+	mapper := lookupTypeMapper(driverName, fieldDescriptor.Type)
+	if mapper == nil {
 		err = fmt.Errorf("unknown field type %s", fieldDescriptor.Type)
 		return
 	}
+	goType, fieldClass, importPath, err = mapper(fieldDescriptor)
+	if err != nil {
+		return
+	}
 	if fieldDescriptor.Unsigned && strings.HasPrefix(goType, "int") {
 		goType = "u" + goType
 	}
@@ -126,6 +239,10 @@ func getSchemaFetcherFactory(driverName string) func(db *sql.DB) schemaFetcher {
 		return newSQLite3SchemaFetcher
 	case "postgres":
 		return newPostgresSchemaFetcher
+	case "clickhouse":
+		return newClickHouseSchemaFetcher
+	case "sqlserver":
+		return newMSSQLSchemaFetcher
 	default:
 		_, _ = fmt.Fprintln(os.Stderr, "unsupported driver "+driverName)
 		os.Exit(2)
@@ -143,12 +260,23 @@ func ensureIdentifier(name string) string {
 	return result
 }
 
-func newBuffWithBaseHeader(packageName string) *bytes.Buffer {
+func newBuffWithBaseHeader(packageName string, extraImports []string) *bytes.Buffer {
 	var buf bytes.Buffer
 	buf.WriteString("// This file is generated by sqlingo (https://github.com/lqs/sqlingo)\n")
 	buf.WriteString("// DO NOT EDIT.\n\n")
 	buf.WriteString("package " + ensureIdentifier(packageName) + "_dsl\n")
-	buf.WriteString("import \"github.com/lqs/sqlingo\"\n\n")
+
+	imports := append([]string{"github.com/lqs/sqlingo"}, extraImports...)
+	sort.Strings(imports[1:])
+	if len(imports) == 1 {
+		buf.WriteString("import " + strconv.Quote(imports[0]) + "\n\n")
+	} else {
+		buf.WriteString("import (\n")
+		for _, imp := range imports {
+			buf.WriteString("\t" + strconv.Quote(imp) + "\n")
+		}
+		buf.WriteString(")\n\n")
+	}
 	return &buf
 }
 
@@ -157,6 +285,9 @@ var (
 	databaseConnection = flag.String("dbc", "", "database connection")
 	tables             = flag.String("t", "", "-t table1,table2,...")
 	forcecases         = flag.String("forcecases", "", "-forcecases ID,IDs,HTML")
+	decimalMode        = flag.String("decimal", "decimal", "-decimal=float64 to render decimal/numeric/money columns as float64 instead of shopspring/decimal.Decimal")
+	tagCase            = flag.String("tagcase", "snake", "-tagcase snake|camel|pascal for the json tag of generated model fields (db tag always uses the column name)")
+	migrate            = flag.Bool("migrate", false, "-migrate to write a migration for the schema changes since the last run instead of generating model code")
 )
 
 // Generate generates code for the given driverName.
@@ -194,7 +325,7 @@ func Generate(driverName string, exampleDataSourceName string) error {
 		return errors.New("no database selected")
 	}
 
-	var buf = newBuffWithBaseHeader(dbName)
+	var buf = newBuffWithBaseHeader(dbName, nil)
 
 	buf.WriteString("type sqlingoRuntimeAndGeneratorVersionsShouldBeTheSame uint32\n\n")
 
@@ -218,6 +349,14 @@ func Generate(driverName string, exampleDataSourceName string) error {
 	buf.WriteString("\tsqlingo.BooleanField\n")
 	buf.WriteString("}\n\n")
 
+	buf.WriteString("type decimalField interface {\n")
+	buf.WriteString("\tsqlingo.DecimalField\n")
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("type bytesField interface {\n")
+	buf.WriteString("\tsqlingo.BytesField\n")
+	buf.WriteString("}\n\n")
+
 	if len(options.tableNames) == 0 {
 		options.tableNames, err = schemaFetcher.GetTableNames()
 		if err != nil {
@@ -232,7 +371,7 @@ func Generate(driverName string, exampleDataSourceName string) error {
 
 	for _, tableName := range options.tableNames {
 		println("Generating", tableName)
-		err = generateTable(schemaFetcher, dbName, tableName, options.forceCases)
+		err = generateTable(driverName, schemaFetcher, dbName, tableName, options.forceCases, *tagCase)
 		if err != nil {
 			return err
 		}
@@ -260,7 +399,7 @@ func generateGetTable(buf *bytes.Buffer, options options) {
 	buf.WriteString("}\n\n")
 }
 
-func generateTable(schemaFetcher schemaFetcher, dbName, tableName string, forceCases []string) error {
+func generateTable(driverName string, schemaFetcher schemaFetcher, dbName, tableName string, forceCases []string, tagCase string) error {
 	fieldDescriptors, err := schemaFetcher.GetFieldDescriptors(tableName)
 	if err != nil {
 		return err
@@ -277,13 +416,17 @@ func generateTable(schemaFetcher schemaFetcher, dbName, tableName string, forceC
 	)
 	objectLines.WriteString(fmt.Sprintf("\ttable: %s,\n\n", tableObjectName))
 
+	importSet := map[string]bool{}
 	for _, fieldDescriptor := range fieldDescriptors {
 
 		goName := convertToExportedIdentifier(fieldDescriptor.Name, forceCases)
-		goType, fieldClass, err := getType(fieldDescriptor)
+		goType, fieldClass, importPath, err := getType(driverName, fieldDescriptor)
 		if err != nil {
 			return err
 		}
+		if importPath != "" {
+			importSet[importPath] = true
+		}
 
 		privateFieldClass := string(fieldClass[0]+'a'-'A') + fieldClass[1:]
 
@@ -298,7 +441,8 @@ func generateTable(schemaFetcher schemaFetcher, dbName, tableName string, forceC
 		tableLines.WriteString(fmt.Sprintf("\t%s %s\n", goName, fieldStructName))
 
 		modelLines.WriteString(commentLine)
-		modelLines.WriteString(fmt.Sprintf("\t%s %s\n", goName, goType))
+		modelTag := fmt.Sprintf("`db:%s json:%s`", strconv.Quote(fieldDescriptor.Name), strconv.Quote(renderTagName(fieldDescriptor.Name, tagCase)))
+		modelLines.WriteString(fmt.Sprintf("\t%s %s %s\n", goName, goType, modelTag))
 
 		objectLines.WriteString(commentLine)
 		objectLines.WriteString(fmt.Sprintf("\t%s: %s{", goName, fieldStructName))
@@ -323,7 +467,11 @@ func generateTable(schemaFetcher schemaFetcher, dbName, tableName string, forceC
 		values.WriteString(fmt.Sprintf("m.%s, ", goName))
 	}
 
-	var buf = newBuffWithBaseHeader(dbName)
+	var extraImports []string
+	for importPath := range importSet {
+		extraImports = append(extraImports, importPath)
+	}
+	var buf = newBuffWithBaseHeader(dbName, extraImports)
 	buf.WriteString("")
 	buf.WriteString(fmt.Sprintf("type %s struct {\n\ttable\n\n", tableStructName))
 	buf.WriteString(tableLines.String())
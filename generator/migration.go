@@ -0,0 +1,298 @@
+package generator
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// schemaSnapshot is the persisted shape of outpath/migrations/schema.json,
+// used to diff the live schema against the schema seen on the previous run.
+type schemaSnapshot struct {
+	Tables map[string][]fieldDescriptor `json:"tables"`
+}
+
+var migrationFileNameRegexp = regexp.MustCompile(`^(\d+)_`)
+
+// Run generates model code for driverName via Generate, or, when invoked
+// with -migrate, writes a migration for the schema changes since the last
+// run via GenerateMigrations instead. It is the entrypoint the sqlingo-gen-*
+// commands call so that migration generation is reachable without writing
+// Go code against the generator package directly.
+func Run(driverName string, exampleDataSourceName string) error {
+	flag.Parse()
+	if *migrate {
+		return GenerateMigrations(driverName, exampleDataSourceName)
+	}
+	return Generate(driverName, exampleDataSourceName)
+}
+
+// GenerateMigrations compares the live schema fetched via schemaFetcher
+// against the schema snapshot recorded on the previous run and writes
+// sequentially numbered up/down .sql files (one past the highest number
+// already in the directory) into outpath/migrations/, using the
+// NNNN_description.up.sql / NNNN_description.down.sql naming convention
+// understood by golang-migrate/mattes-migrate (and sqlingo.Migrate).
+func GenerateMigrations(driverName string, exampleDataSourceName string) error {
+	flag.Parse()
+	if len(*outputPath) == 0 || len(*databaseConnection) == 0 {
+		printUsageAndExit(exampleDataSourceName)
+	}
+
+	var options options
+	options.dataSourceName = *databaseConnection
+	if len(*tables) != 0 {
+		options.tableNames = strings.Split(*tables, ",")
+	}
+
+	db, err := sql.Open(driverName, options.dataSourceName)
+	if err != nil {
+		return err
+	}
+
+	schemaFetcherFactory := getSchemaFetcherFactory(driverName)
+	schemaFetcher := schemaFetcherFactory(db)
+
+	if len(options.tableNames) == 0 {
+		options.tableNames, err = schemaFetcher.GetTableNames()
+		if err != nil {
+			return err
+		}
+	}
+
+	liveSchema := schemaSnapshot{Tables: map[string][]fieldDescriptor{}}
+	for _, tableName := range options.tableNames {
+		fieldDescriptors, err := schemaFetcher.GetFieldDescriptors(tableName)
+		if err != nil {
+			return err
+		}
+		liveSchema.Tables[tableName] = fieldDescriptors
+	}
+
+	migrationsDir := filepath.Join(*outputPath, "migrations")
+	if err := os.MkdirAll(migrationsDir, 0755); err != nil {
+		return err
+	}
+
+	snapshotPath := filepath.Join(migrationsDir, "schema.json")
+	previousSchema, err := readSchemaSnapshot(snapshotPath)
+	if err != nil {
+		return err
+	}
+
+	up, down, description := diffSchema(driverName, schemaFetcher, previousSchema, liveSchema)
+	if up.Len() == 0 {
+		println("No schema changes detected")
+		return writeSchemaSnapshot(snapshotPath, liveSchema)
+	}
+
+	nextVersion, err := nextMigrationVersion(migrationsDir)
+	if err != nil {
+		return err
+	}
+
+	basename := fmt.Sprintf("%04d_%s", nextVersion, description)
+	if err := os.WriteFile(filepath.Join(migrationsDir, basename+".up.sql"), up.Bytes(), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(migrationsDir, basename+".down.sql"), down.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	println("Generated migration", basename)
+	return writeSchemaSnapshot(snapshotPath, liveSchema)
+}
+
+// diffSchema synthesizes per-dialect DDL (using schemaFetcher.QuoteIdentifier
+// for identifier quoting and writeColumnChange for driverName-specific ALTER
+// syntax) that migrates the database from previousSchema to liveSchema,
+// along with the inverse statements and a short description derived from the
+// affected tables.
+func diffSchema(driverName string, schemaFetcher schemaFetcher, previousSchema, liveSchema schemaSnapshot) (up, down *bytes.Buffer, description string) {
+	up = &bytes.Buffer{}
+	down = &bytes.Buffer{}
+	var changedTables []string
+
+	var tableNames []string
+	for tableName := range liveSchema.Tables {
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+
+	for _, tableName := range tableNames {
+		liveFields := liveSchema.Tables[tableName]
+		previousFields, existed := previousSchema.Tables[tableName]
+		quotedTable := schemaFetcher.QuoteIdentifier(tableName)
+
+		if !existed {
+			fmt.Fprintf(up, "CREATE TABLE %s (\n", quotedTable)
+			for i, field := range liveFields {
+				comma := ","
+				if i == len(liveFields)-1 {
+					comma = ""
+				}
+				fmt.Fprintf(up, "  %s %s%s\n", schemaFetcher.QuoteIdentifier(field.Name), columnDefinition(field), comma)
+			}
+			up.WriteString(");\n")
+			fmt.Fprintf(down, "DROP TABLE %s;\n", quotedTable)
+			changedTables = append(changedTables, tableName)
+			continue
+		}
+
+		previousFieldsByName := map[string]fieldDescriptor{}
+		for _, field := range previousFields {
+			previousFieldsByName[field.Name] = field
+		}
+		liveFieldsByName := map[string]fieldDescriptor{}
+		for _, field := range liveFields {
+			liveFieldsByName[field.Name] = field
+		}
+
+		tableChanged := false
+		for _, field := range liveFields {
+			quotedColumn := schemaFetcher.QuoteIdentifier(field.Name)
+			previousField, existedBefore := previousFieldsByName[field.Name]
+			if !existedBefore {
+				fmt.Fprintf(up, "ALTER TABLE %s ADD COLUMN %s %s;\n", quotedTable, quotedColumn, columnDefinition(field))
+				fmt.Fprintf(down, "ALTER TABLE %s DROP COLUMN %s;\n", quotedTable, quotedColumn)
+				tableChanged = true
+			} else if previousField != field {
+				writeColumnChange(up, driverName, quotedTable, quotedColumn, field)
+				writeColumnChange(down, driverName, quotedTable, quotedColumn, previousField)
+				tableChanged = true
+			}
+		}
+		for _, field := range previousFields {
+			if _, stillExists := liveFieldsByName[field.Name]; !stillExists {
+				quotedColumn := schemaFetcher.QuoteIdentifier(field.Name)
+				fmt.Fprintf(up, "ALTER TABLE %s DROP COLUMN %s;\n", quotedTable, quotedColumn)
+				fmt.Fprintf(down, "ALTER TABLE %s ADD COLUMN %s %s;\n", quotedTable, quotedColumn, columnDefinition(field))
+				tableChanged = true
+			}
+		}
+
+		if tableChanged {
+			changedTables = append(changedTables, tableName)
+		}
+	}
+
+	for tableName := range previousSchema.Tables {
+		if _, stillExists := liveSchema.Tables[tableName]; !stillExists {
+			quotedTable := schemaFetcher.QuoteIdentifier(tableName)
+			fmt.Fprintf(up, "DROP TABLE %s;\n", quotedTable)
+			down.WriteString("-- original CREATE TABLE statement for " + tableName + " is not recoverable\n")
+			changedTables = append(changedTables, tableName)
+		}
+	}
+
+	description = "update_schema"
+	if len(changedTables) > 0 {
+		description = strings.Join(changedTables, "_")
+		if len(description) > 60 {
+			description = description[:60]
+		}
+	}
+	return up, down, description
+}
+
+// writeColumnChange emits the DDL for driverName that alters an existing
+// column to field's type/nullability. MySQL and ClickHouse support a single
+// MODIFY COLUMN statement; SQL Server takes the type and the NULL/NOT NULL
+// constraint together in a single ALTER COLUMN statement; Postgres requires
+// them to be changed in separate ALTER COLUMN statements; SQLite3 has no
+// ALTER COLUMN at all, so the change is left as a
+// comment describing the table rebuild it requires.
+func writeColumnChange(buf *bytes.Buffer, driverName, quotedTable, quotedColumn string, field fieldDescriptor) {
+	switch driverName {
+	case "postgres":
+		fmt.Fprintf(buf, "ALTER TABLE %s ALTER COLUMN %s TYPE %s;\n", quotedTable, quotedColumn, columnType(field))
+		if field.AllowNull {
+			fmt.Fprintf(buf, "ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;\n", quotedTable, quotedColumn)
+		} else {
+			fmt.Fprintf(buf, "ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;\n", quotedTable, quotedColumn)
+		}
+	case "sqlserver":
+		fmt.Fprintf(buf, "ALTER TABLE %s ALTER COLUMN %s %s;\n", quotedTable, quotedColumn, columnDefinition(field))
+	case "sqlite3":
+		fmt.Fprintf(buf, "-- sqlite3 has no ALTER COLUMN; rebuild %s so that %s is %s\n", quotedTable, quotedColumn, columnDefinition(field))
+	default:
+		fmt.Fprintf(buf, "ALTER TABLE %s MODIFY COLUMN %s %s;\n", quotedTable, quotedColumn, columnDefinition(field))
+	}
+}
+
+// columnType renders field's SQL type, including its Size and, for
+// decimal-like types, its Scale (e.g. "decimal(20,4)"), without the
+// NULL/NOT NULL constraint, for dialects whose ALTER COLUMN syntax changes
+// the type and the constraint in separate statements.
+func columnType(field fieldDescriptor) string {
+	sqlType := field.Type
+	if field.Size > 0 {
+		if field.Scale > 0 {
+			sqlType = fmt.Sprintf("%s(%d,%d)", sqlType, field.Size, field.Scale)
+		} else {
+			sqlType = fmt.Sprintf("%s(%d)", sqlType, field.Size)
+		}
+	}
+	if field.Unsigned {
+		sqlType += " UNSIGNED"
+	}
+	return sqlType
+}
+
+func columnDefinition(field fieldDescriptor) string {
+	sqlType := columnType(field)
+	if field.AllowNull {
+		return sqlType + " NULL"
+	}
+	return sqlType + " NOT NULL"
+}
+
+func readSchemaSnapshot(path string) (schemaSnapshot, error) {
+	snapshot := schemaSnapshot{Tables: map[string][]fieldDescriptor{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return snapshot, nil
+	}
+	if err != nil {
+		return snapshot, err
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return snapshot, err
+	}
+	return snapshot, nil
+}
+
+func writeSchemaSnapshot(path string, snapshot schemaSnapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func nextMigrationVersion(migrationsDir string) (int, error) {
+	entries, err := os.ReadDir(migrationsDir)
+	if err != nil {
+		return 0, err
+	}
+	max := 0
+	for _, entry := range entries {
+		matches := migrationFileNameRegexp.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+		version, err := strconv.Atoi(matches[1])
+		if err == nil && version > max {
+			max = version
+		}
+	}
+	return max + 1, nil
+}
@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"database/sql"
+	"strings"
+)
+
+type mssqlSchemaFetcher struct {
+	db *sql.DB
+}
+
+func newMSSQLSchemaFetcher(db *sql.DB) schemaFetcher {
+	return mssqlSchemaFetcher{db: db}
+}
+
+func (f mssqlSchemaFetcher) GetDatabaseName() (dbName string, err error) {
+	err = f.db.QueryRow("SELECT DB_NAME()").Scan(&dbName)
+	return
+}
+
+func (f mssqlSchemaFetcher) GetTableNames() (tableNames []string, err error) {
+	rows, err := f.db.Query(
+		"SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName string
+		if err = rows.Scan(&tableName); err != nil {
+			return
+		}
+		tableNames = append(tableNames, tableName)
+	}
+	err = rows.Err()
+	return
+}
+
+func (f mssqlSchemaFetcher) GetFieldDescriptors(tableName string) (fieldDescriptors []fieldDescriptor, err error) {
+	rows, err := f.db.Query(`
+		SELECT
+			c.COLUMN_NAME,
+			c.DATA_TYPE,
+			ISNULL(c.CHARACTER_MAXIMUM_LENGTH, ISNULL(c.NUMERIC_PRECISION, 0)),
+			ISNULL(c.NUMERIC_SCALE, 0),
+			CASE WHEN c.IS_NULLABLE = 'YES' THEN 1 ELSE 0 END,
+			ISNULL(CAST(ep.value AS nvarchar(max)), '')
+		FROM INFORMATION_SCHEMA.COLUMNS c
+		LEFT JOIN sys.extended_properties ep
+			ON ep.major_id = OBJECT_ID(c.TABLE_NAME)
+			AND ep.minor_id = COLUMNPROPERTY(OBJECT_ID(c.TABLE_NAME), c.COLUMN_NAME, 'ColumnId')
+			AND ep.name = 'MS_Description'
+		WHERE c.TABLE_NAME = ?
+		ORDER BY c.ORDINAL_POSITION`,
+		tableName)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			name, dataType, comment string
+			size, scale             int
+			allowNull               bool
+		)
+		if err = rows.Scan(&name, &dataType, &size, &scale, &allowNull, &comment); err != nil {
+			return
+		}
+		fieldDescriptors = append(fieldDescriptors, fieldDescriptor{
+			Name:      name,
+			Type:      strings.ToLower(dataType),
+			Size:      size,
+			Scale:     scale,
+			AllowNull: allowNull,
+			Comment:   comment,
+		})
+	}
+	err = rows.Err()
+	return
+}
+
+func (f mssqlSchemaFetcher) QuoteIdentifier(identifier string) string {
+	return "[" + strings.ReplaceAll(identifier, "]", "]]") + "]"
+}
+
+func init() {
+	register := func(sqlType, goType, fieldClass string) {
+		RegisterTypeMapper("sqlserver", sqlType, func(fieldDescriptor fieldDescriptor) (string, string, string, error) {
+			return goType, fieldClass, "", nil
+		})
+	}
+	register("tinyint", "uint8", "NumberField")
+	register("smallint", "int16", "NumberField")
+	register("int", "int32", "NumberField")
+	register("bigint", "int64", "NumberField")
+	register("real", "float32", "NumberField")
+	register("float", "float64", "NumberField")
+	register("bit", "bool", "BooleanField")
+	register("char", "string", "StringField")
+	register("varchar", "string", "StringField")
+	register("nchar", "string", "StringField")
+	register("nvarchar", "string", "StringField")
+	register("text", "string", "StringField")
+	register("ntext", "string", "StringField")
+	register("date", "string", "StringField")
+	register("time", "string", "StringField")
+	register("datetime", "string", "StringField")
+	register("datetime2", "string", "StringField")
+	register("smalldatetime", "string", "StringField")
+	register("datetimeoffset", "string", "StringField")
+	register("uniqueidentifier", "string", "StringField")
+	for _, sqlType := range []string{"decimal", "numeric", "money", "smallmoney"} {
+		RegisterTypeMapper("sqlserver", sqlType, mapDecimalType)
+	}
+	for _, sqlType := range []string{"binary", "varbinary", "image"} {
+		RegisterTypeMapper("sqlserver", sqlType, mapBytesType)
+	}
+}
@@ -0,0 +1,36 @@
+package generator
+
+import "strings"
+
+// TypeMapper renders a column as a Go type. It returns the field's Go type,
+// the sqlingo field class used to build it (e.g. "NumberField"), and the
+// import path that Go type lives in, if any.
+type TypeMapper func(fieldDescriptor fieldDescriptor) (goType, fieldClass, importPath string, err error)
+
+var typeMappers = map[string]map[string]TypeMapper{}
+
+// RegisterTypeMapper overrides how a column is rendered by the generator, so
+// downstream users can customize column types without forking sqlingo-gen.
+// For example a user could map decimal(20,4) to shopspring/decimal.Decimal
+// with a DecimalField class, map uuid columns to github.com/google/uuid.UUID,
+// or map Postgres jsonb to a typed struct via json.RawMessage.
+//
+// driverName selects which driver the mapping applies to ("mysql",
+// "postgres", "sqlite3", ...); pass "*" to register a mapping shared by every
+// driver that doesn't have a more specific one registered for the same
+// sqlType. sqlType is matched case-insensitively against fieldDescriptor.Type.
+func RegisterTypeMapper(driverName, sqlType string, mapper TypeMapper) {
+	sqlType = strings.ToLower(sqlType)
+	if typeMappers[driverName] == nil {
+		typeMappers[driverName] = map[string]TypeMapper{}
+	}
+	typeMappers[driverName][sqlType] = mapper
+}
+
+func lookupTypeMapper(driverName, sqlType string) TypeMapper {
+	sqlType = strings.ToLower(sqlType)
+	if mapper, ok := typeMappers[driverName][sqlType]; ok {
+		return mapper
+	}
+	return typeMappers["*"][sqlType]
+}
@@ -0,0 +1,150 @@
+package sqlingo
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migrationFileNameRegexp matches the golang-migrate/mattes-migrate file
+// naming convention, e.g. 0001_create_users.up.sql / 0001_create_users.down.sql,
+// so that a directory generated by sqlingo-gen can also be applied with any
+// migrate-compatible CLI.
+var migrationFileNameRegexp = regexp.MustCompile(`^(\d+)_.*\.(up|down)\.sql$`)
+
+// Migrate applies all pending *.up.sql migration files in dir to db, in
+// ascending version order, one file per transaction. Progress is tracked in a
+// schema_migrations(version bigint primary key, dirty bool) table: if a
+// migration fails, its version is recorded as dirty and Migrate returns an
+// error on every subsequent call until the table is fixed up by hand, the
+// same protocol followed by golang-migrate/migrate.
+func Migrate(db *sql.DB, dir string) error {
+	if err := ensureSchemaMigrationsTable(db); err != nil {
+		return err
+	}
+
+	version, dirty, err := getSchemaMigrationsVersion(db)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("database is dirty at version %d, fix it manually before migrating", version)
+	}
+
+	files, err := pendingMigrationFiles(dir, version)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := applyMigrationFile(db, dir, file); err != nil {
+			_ = setSchemaMigrationsVersion(db, file.version, true)
+			return fmt.Errorf("migration %d failed: %w", file.version, err)
+		}
+		if err := setSchemaMigrationsVersion(db, file.version, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type migrationFile struct {
+	version int64
+	name    string
+}
+
+func pendingMigrationFiles(dir string, afterVersion int64) ([]migrationFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []migrationFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		matches := migrationFileNameRegexp.FindStringSubmatch(entry.Name())
+		if matches == nil || matches[2] != "up" {
+			continue
+		}
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		if version <= afterVersion {
+			continue
+		}
+		files = append(files, migrationFile{version: version, name: entry.Name()})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].version < files[j].version })
+	return files, nil
+}
+
+func applyMigrationFile(db *sql.DB, dir string, file migrationFile) error {
+	content, err := os.ReadFile(filepath.Join(dir, file.name))
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, statement := range strings.Split(stripSQLComments(string(content)), ";") {
+		statement = strings.TrimSpace(statement)
+		if statement == "" {
+			continue
+		}
+		if _, err := tx.Exec(statement); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// stripSQLComments removes whole-line "--" comments from content before it's
+// split on ";", so a comment that itself contains a semicolon (e.g. one
+// describing a DDL change the generator couldn't express, such as sqlite3's
+// lack of ALTER COLUMN) doesn't get split into a bogus statement.
+func stripSQLComments(content string) string {
+	lines := strings.Split(content, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
+
+func ensureSchemaMigrationsTable(db *sql.DB) error {
+	_, err := db.Exec("CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT PRIMARY KEY, dirty BOOLEAN NOT NULL)")
+	return err
+}
+
+func getSchemaMigrationsVersion(db *sql.DB) (version int64, dirty bool, err error) {
+	row := db.QueryRow("SELECT version, dirty FROM schema_migrations ORDER BY version DESC LIMIT 1")
+	err = row.Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+func setSchemaMigrationsVersion(db *sql.DB, version int64, dirty bool) error {
+	if _, err := db.Exec("DELETE FROM schema_migrations"); err != nil {
+		return err
+	}
+	_, err := db.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)", version, dirty)
+	return err
+}
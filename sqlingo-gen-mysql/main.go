@@ -6,7 +6,7 @@ import (
 )
 
 func main() {
-	err := generator.Generate("mysql", "username:password@tcp(hostname:3306)/database")
+	err := generator.Run("mysql", "username:password@tcp(hostname:3306)/database")
 	if err != nil {
 		panic(err)
 	}
@@ -6,7 +6,7 @@ import (
 )
 
 func main() {
-	err := generator.Generate("postgres", "host=localhost port=5432 user=user password=pass dbname=db sslmode=disable")
+	err := generator.Run("postgres", "host=localhost port=5432 user=user password=pass dbname=db sslmode=disable")
 	if err != nil {
 		panic(err)
 	}
@@ -6,7 +6,7 @@ import (
 )
 
 func main() {
-	err := generator.Generate("mysql", "./testdb.sqlite3")
+	err := generator.Run("sqlite3", "./testdb.sqlite3")
 	if err != nil {
 		panic(err)
 	}
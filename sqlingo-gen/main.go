@@ -18,7 +18,7 @@ func main() {
 		"\u001b[0m",
 	}
 	_, _ = fmt.Fprintln(os.Stderr, strings.Join(warningLines, "\n"))
-	err := generator.Generate("mysql", "username:password@tcp(hostname:3306)/database")
+	err := generator.Run("mysql", "username:password@tcp(hostname:3306)/database")
 	if err != nil {
 		panic(err)
 	}